@@ -0,0 +1,202 @@
+package v1
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// GitJob represents a single reconciliation of a git repository into a
+// Kubernetes Job that clones it and runs a user-supplied workload against
+// the checkout.
+type GitJob struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GitJobSpec   `json:"spec,omitempty"`
+	Status GitJobStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// GitJobList is a list of GitJob resources.
+type GitJobList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []GitJob `json:"items"`
+}
+
+// GitJobSpec describes the repository to clone and the Job template to run
+// against the resulting checkout.
+type GitJobSpec struct {
+	// Git is retained for backwards compatibility with GitJobs that don't
+	// set Source. It is equivalent to Source{Type: SourceTypeGit, Git: Git}.
+	Git     GitInfo         `json:"git,omitempty"`
+	Source  Source          `json:"source,omitempty"`
+	JobSpec batchv1.JobSpec `json:"jobSpec,omitempty"`
+
+	// PodSecurity is the Pod Security Admission level the generated Job's
+	// Pod must satisfy. Defaults to PodSecurityRestricted.
+	PodSecurity PodSecurityLevel `json:"podSecurity,omitempty"`
+}
+
+// PodSecurityLevel is one of the Pod Security Admission levels defined at
+// https://kubernetes.io/docs/concepts/security/pod-security-standards/.
+type PodSecurityLevel string
+
+const (
+	PodSecurityPrivileged PodSecurityLevel = "privileged"
+	PodSecurityBaseline   PodSecurityLevel = "baseline"
+	PodSecurityRestricted PodSecurityLevel = "restricted"
+)
+
+// SourceType selects which ResourceFetcher populates the init container's
+// /workspace.
+type SourceType string
+
+const (
+	SourceTypeGit  SourceType = "git"
+	SourceTypeOCI  SourceType = "oci"
+	SourceTypeHTTP SourceType = "http"
+	SourceTypeGCS  SourceType = "gcs"
+	SourceTypeS3   SourceType = "s3"
+)
+
+// Source describes a non-git-exclusive resource to fetch into /workspace.
+// Exactly one of the type-specific fields should be set, matching Type.
+type Source struct {
+	Type SourceType `json:"type,omitempty"`
+
+	Git  *GitInfo    `json:"git,omitempty"`
+	OCI  *OCISource  `json:"oci,omitempty"`
+	HTTP *HTTPSource `json:"http,omitempty"`
+	GCS  *GCSSource  `json:"gcs,omitempty"`
+	S3   *S3Source   `json:"s3,omitempty"`
+}
+
+// OCISource fetches the filesystem layers of an OCI artifact or image.
+type OCISource struct {
+	Repo       string     `json:"repo,omitempty"`
+	Tag        string     `json:"tag,omitempty"`
+	Digest     string     `json:"digest,omitempty"`
+	Credential Credential `json:"credential,omitempty"`
+}
+
+// HTTPSource fetches and extracts an HTTP(S) tarball.
+type HTTPSource struct {
+	URL        string     `json:"url,omitempty"`
+	Credential Credential `json:"credential,omitempty"`
+}
+
+// GCSSource fetches an object (or prefix) from a GCS bucket.
+type GCSSource struct {
+	Bucket     string     `json:"bucket,omitempty"`
+	Object     string     `json:"object,omitempty"`
+	Credential Credential `json:"credential,omitempty"`
+}
+
+// S3Source fetches an object (or prefix) from an S3-compatible bucket.
+type S3Source struct {
+	Bucket     string     `json:"bucket,omitempty"`
+	Key        string     `json:"key,omitempty"`
+	Region     string     `json:"region,omitempty"`
+	Credential Credential `json:"credential,omitempty"`
+}
+
+// GitInfo describes the git repository that should be cloned into the
+// gitcloner init container's workspace, along with how to authenticate to
+// it and verify its TLS certificate.
+type GitInfo struct {
+	Repo       string     `json:"repo,omitempty"`
+	Branch     string     `json:"branch,omitempty"`
+	Revision   string     `json:"revision,omitempty"`
+	Credential Credential `json:"credential,omitempty"`
+
+	// Depth creates a shallow clone truncated to the specified number of
+	// commits. Zero means a full clone.
+	Depth int32 `json:"depth,omitempty"`
+
+	// SingleBranch passes --single-branch to gitcloner so that only one
+	// branch is cloned instead of all branches. Branch is not passed to
+	// gitcloner, so the branch cloned is the repository's default branch.
+	SingleBranch bool `json:"singleBranch,omitempty"`
+
+	// Submodules controls how the repository's submodules, if any, are
+	// fetched. Defaults to SubmodulesNone.
+	Submodules SubmodulesMode `json:"submodules,omitempty"`
+
+	// PartialCloneFilter enables a partial clone using the given
+	// git filter-spec, e.g. "blob:none" or "tree:0".
+	PartialCloneFilter string `json:"partialCloneFilter,omitempty"`
+}
+
+// SubmodulesMode controls how gitcloner fetches a repository's submodules.
+type SubmodulesMode string
+
+const (
+	// SubmodulesNone leaves submodules uninitialized, the git default.
+	SubmodulesNone SubmodulesMode = "none"
+	// SubmodulesShallow initializes submodules with a depth-1 clone.
+	SubmodulesShallow SubmodulesMode = "shallow"
+	// SubmodulesRecursive initializes submodules and their own
+	// submodules, recursively, with a full clone.
+	SubmodulesRecursive SubmodulesMode = "recursive"
+)
+
+// Credential configures how the gitcloner init container authenticates to
+// Git. ClientSecretName, when set, must reference a secret of type
+// corev1.SecretTypeBasicAuth or corev1.SecretTypeSSHAuth in the GitJob's
+// namespace. GitHubApp and OIDC are alternatives to ClientSecretName for
+// providers that support short-lived, minted-at-runtime tokens instead of a
+// long-lived secret.
+type Credential struct {
+	ClientSecretName      string `json:"clientSecretName,omitempty"`
+	CABundle              []byte `json:"caBundle,omitempty"`
+	InsecureSkipTLSverify bool   `json:"insecureSkipTLSverify,omitempty"`
+
+	GitHubApp *GitHubAppCredential `json:"githubApp,omitempty"`
+	OIDC      *OIDCCredential      `json:"oidc,omitempty"`
+}
+
+// GitHubAppCredential authenticates as a GitHub App installation. The
+// gitcloner init container exchanges the private key referenced by
+// PrivateKeySecretRef for a short-lived installation access token at
+// runtime, rather than reading a static credential from the API server.
+type GitHubAppCredential struct {
+	AppID               int64                    `json:"appID,omitempty"`
+	InstallationID      int64                    `json:"installationID,omitempty"`
+	PrivateKeySecretRef corev1.SecretKeySelector `json:"privateKeySecretRef,omitempty"`
+}
+
+// OIDCCredential exchanges a projected Kubernetes service account token for
+// a short-lived cloud-provider token, which is then used as the HTTP basic
+// auth password when cloning (e.g. AWS CodeCommit, GCP Source Repositories).
+type OIDCCredential struct {
+	// Audience is the intended audience of the projected service account
+	// token, e.g. the STS endpoint of the provider performing the
+	// exchange.
+	Audience string `json:"audience,omitempty"`
+}
+
+// GitJobStatus reports the last observed state of the git repository and
+// the Job created to reconcile it.
+type GitJobStatus struct {
+	GitEvent GitEvent `json:"gitEvent,omitempty"`
+}
+
+// GitEvent records the commit that triggered the most recent Job, along
+// with any webhook metadata that accompanied it.
+type GitEvent struct {
+	Commit     string     `json:"commit,omitempty"`
+	GithubMeta GithubMeta `json:"githubMeta,omitempty"`
+}
+
+// GithubMeta carries the subset of a GitHub webhook payload that gitjob
+// surfaces to the Job it creates.
+type GithubMeta struct {
+	Event string `json:"event,omitempty"`
+}
@@ -0,0 +1,36 @@
+// Package gitjob reconciles v1.GitJob resources into Kubernetes Jobs that
+// clone a git repository and run a user-supplied workload against the
+// checkout.
+package gitjob
+
+import (
+	corev1controller "github.com/rancher/wrangler/pkg/generated/controllers/core/v1"
+)
+
+// Handler generates and reconciles the Job backing a GitJob.
+type Handler struct {
+	image   string
+	secrets corev1controller.SecretCache
+
+	// secretClient creates and updates the imagePullSecrets synthesized for
+	// private registryCredentials matches. It is nil-safe: when unset,
+	// generateJob still attaches the computed ImagePullSecrets reference but
+	// leaves creating the Secret itself to whatever does set it up.
+	secretClient secretGetCreateUpdater
+
+	// registryCredentials are the controller-level credentials used to
+	// synthesize imagePullSecrets for private gitcloner/user images.
+	registryCredentials []RegistryCredential
+}
+
+// NewHandler returns a Handler that builds gitcloner init containers using
+// image, resolves credential secrets through secrets, and synthesizes
+// imagePullSecrets from registryCredentials through secretClient.
+func NewHandler(image string, secrets corev1controller.SecretCache, secretClient secretGetCreateUpdater, registryCredentials []RegistryCredential) *Handler {
+	return &Handler{
+		image:               image,
+		secrets:             secrets,
+		secretClient:        secretClient,
+		registryCredentials: registryCredentials,
+	}
+}
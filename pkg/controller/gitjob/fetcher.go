@@ -0,0 +1,166 @@
+package gitjob
+
+import (
+	"fmt"
+
+	v1 "github.com/rancher/gitjob/pkg/apis/gitjob.cattle.io/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ResourceFetcher populates /workspace from one kind of v1.Source. It
+// contributes its own init container command/args and any extra
+// volumes/volume mounts it needs, reusing the shared workspace/tmp
+// emptyDirs that generateJob always sets up and the securityContext that
+// generateJob derives from the GitJob's PodSecurity level.
+//
+// This mirrors Tekton's PipelineResource split: each source type owns how
+// it is fetched, while generateJob owns the Job/Pod scaffolding common to
+// all of them.
+type ResourceFetcher interface {
+	// FetchContainer returns the command and args to run in the init
+	// container, plus any volumes/volumeMounts beyond the shared ones
+	// generateJob already provides.
+	FetchContainer(h *Handler, gitjob *v1.GitJob) (command, args []string, volumes []corev1.Volume, volumeMounts []corev1.VolumeMount, err error)
+}
+
+// fetcherRegistry maps a v1.SourceType to the ResourceFetcher that handles
+// it. Out-of-tree fetchers can register themselves via RegisterFetcher.
+var fetcherRegistry = map[v1.SourceType]ResourceFetcher{}
+
+// RegisterFetcher makes fetcher available for sourceType. It is intended to
+// be called from an init function, including by out-of-tree packages that
+// want to plug in additional source types.
+func RegisterFetcher(sourceType v1.SourceType, fetcher ResourceFetcher) {
+	fetcherRegistry[sourceType] = fetcher
+}
+
+func init() {
+	RegisterFetcher(v1.SourceTypeGit, gitFetcher{})
+	RegisterFetcher(v1.SourceTypeOCI, ociFetcher{})
+	RegisterFetcher(v1.SourceTypeHTTP, httpFetcher{})
+	RegisterFetcher(v1.SourceTypeGCS, gcsFetcher{})
+	RegisterFetcher(v1.SourceTypeS3, s3Fetcher{})
+}
+
+// newFetcher looks up the ResourceFetcher for gitjob's source, defaulting
+// to gitFetcher for GitJobs that only set the legacy Git field.
+func newFetcher(gitjob *v1.GitJob) (ResourceFetcher, error) {
+	sourceType := gitjob.Spec.Source.Type
+	if sourceType == "" {
+		sourceType = v1.SourceTypeGit
+	}
+	fetcher, ok := fetcherRegistry[sourceType]
+	if !ok {
+		return nil, fmt.Errorf("no resource fetcher registered for source type %q", sourceType)
+	}
+	return fetcher, nil
+}
+
+// gitFetcher clones gitjob.Spec.Source.Git (or, for backwards compatibility,
+// gitjob.Spec.Git when Source isn't set) using the gitcloner binary.
+type gitFetcher struct{}
+
+func (gitFetcher) FetchContainer(h *Handler, gitjob *v1.GitJob) ([]string, []string, []corev1.Volume, []corev1.VolumeMount, error) {
+	gitInfo := gitjob.Spec.Git
+	if gitjob.Spec.Source.Git != nil {
+		gitInfo = *gitjob.Spec.Source.Git
+	}
+	return gitCloneContainer(h, gitjob.Namespace, gitjob.Name, gitInfo)
+}
+
+// ociFetcher pulls the filesystem layers of an OCI artifact or image using
+// the ocicloner binary.
+type ociFetcher struct{}
+
+func (ociFetcher) FetchContainer(h *Handler, gitjob *v1.GitJob) ([]string, []string, []corev1.Volume, []corev1.VolumeMount, error) {
+	oci := gitjob.Spec.Source.OCI
+	if oci == nil {
+		return nil, nil, nil, nil, fmt.Errorf("source type %q requires spec.source.oci", v1.SourceTypeOCI)
+	}
+	ref := oci.Repo
+	if oci.Digest != "" {
+		ref = ref + "@" + oci.Digest
+	} else if oci.Tag != "" {
+		ref = ref + ":" + oci.Tag
+	}
+	args := []string{ref, "/workspace"}
+	a, volumes, mounts, err := credentialArgs(h, gitjob.Namespace, oci.Credential)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	args = append(args, a...)
+	tArgs, tVolumes, tMounts := tlsArgs(gitjob.Name, oci.Credential)
+	args = append(args, tArgs...)
+	volumes = append(volumes, tVolumes...)
+	mounts = append(mounts, tMounts...)
+	return []string{"ocicloner"}, args, volumes, mounts, nil
+}
+
+// httpFetcher downloads and extracts an HTTP(S) tarball using the
+// httpfetcher binary.
+type httpFetcher struct{}
+
+func (httpFetcher) FetchContainer(h *Handler, gitjob *v1.GitJob) ([]string, []string, []corev1.Volume, []corev1.VolumeMount, error) {
+	src := gitjob.Spec.Source.HTTP
+	if src == nil {
+		return nil, nil, nil, nil, fmt.Errorf("source type %q requires spec.source.http", v1.SourceTypeHTTP)
+	}
+	args := []string{src.URL, "/workspace"}
+	a, volumes, mounts, err := credentialArgs(h, gitjob.Namespace, src.Credential)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	args = append(args, a...)
+	tArgs, tVolumes, tMounts := tlsArgs(gitjob.Name, src.Credential)
+	args = append(args, tArgs...)
+	volumes = append(volumes, tVolumes...)
+	mounts = append(mounts, tMounts...)
+	return []string{"httpfetcher"}, args, volumes, mounts, nil
+}
+
+// gcsFetcher downloads an object or prefix from a GCS bucket using the
+// gcsfetcher binary.
+type gcsFetcher struct{}
+
+func (gcsFetcher) FetchContainer(h *Handler, gitjob *v1.GitJob) ([]string, []string, []corev1.Volume, []corev1.VolumeMount, error) {
+	src := gitjob.Spec.Source.GCS
+	if src == nil {
+		return nil, nil, nil, nil, fmt.Errorf("source type %q requires spec.source.gcs", v1.SourceTypeGCS)
+	}
+	args := []string{"gs://" + src.Bucket + "/" + src.Object, "/workspace"}
+	a, volumes, mounts, err := credentialArgs(h, gitjob.Namespace, src.Credential)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	args = append(args, a...)
+	tArgs, tVolumes, tMounts := tlsArgs(gitjob.Name, src.Credential)
+	args = append(args, tArgs...)
+	volumes = append(volumes, tVolumes...)
+	mounts = append(mounts, tMounts...)
+	return []string{"gcsfetcher"}, args, volumes, mounts, nil
+}
+
+// s3Fetcher downloads an object or prefix from an S3-compatible bucket
+// using the s3fetcher binary.
+type s3Fetcher struct{}
+
+func (s3Fetcher) FetchContainer(h *Handler, gitjob *v1.GitJob) ([]string, []string, []corev1.Volume, []corev1.VolumeMount, error) {
+	src := gitjob.Spec.Source.S3
+	if src == nil {
+		return nil, nil, nil, nil, fmt.Errorf("source type %q requires spec.source.s3", v1.SourceTypeS3)
+	}
+	args := []string{"s3://" + src.Bucket + "/" + src.Key, "/workspace"}
+	if src.Region != "" {
+		args = append(args, "--region", src.Region)
+	}
+	a, volumes, mounts, err := credentialArgs(h, gitjob.Namespace, src.Credential)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	args = append(args, a...)
+	tArgs, tVolumes, tMounts := tlsArgs(gitjob.Name, src.Credential)
+	args = append(args, tArgs...)
+	volumes = append(volumes, tVolumes...)
+	mounts = append(mounts, tMounts...)
+	return []string{"s3fetcher"}, args, volumes, mounts, nil
+}
@@ -0,0 +1,343 @@
+package gitjob
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	v1 "github.com/rancher/gitjob/pkg/apis/gitjob.cattle.io/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	gitClonerVolumeName     = "git-cloner"
+	emptyDirVolumeName      = "empty-dir"
+	gitCredentialVolumeName = "git-credential"
+	bundleCAVolumeName      = "additional-ca"
+	bundleCAFile            = "additional-ca.pem"
+
+	githubAppVolumeName = "github-app-private-key"
+	oidcTokenVolumeName = "oidc-token"
+	oidcTokenFile       = "token"
+
+	// knownHostsSecretKey is the optional key an SSH credential Secret may
+	// carry alongside corev1.SSHAuthPrivateKey to pin the server host keys
+	// gitcloner should accept, instead of disabling host key checking.
+	knownHostsSecretKey = "known_hosts"
+
+	commitEnvVar     = "COMMIT"
+	eventTypeEnvVar  = "EVENT_TYPE"
+	httpProxyEnvVar  = "HTTP_PROXY"
+	httpsProxyEnvVar = "HTTPS_PROXY"
+)
+
+// generateJob builds the Job that fetches gitjob's source into a shared
+// workspace and then runs the user-supplied Job template against it. The
+// source is fetched by whichever ResourceFetcher is registered for
+// gitjob.Spec.Source.Type (defaulting to git for backwards compatibility).
+func (h *Handler) generateJob(gitjob *v1.GitJob) (*batchv1.Job, error) {
+	fetcher, err := newFetcher(gitjob)
+	if err != nil {
+		return nil, err
+	}
+
+	command, args, fetcherVolumes, fetcherMounts, err := fetcher.FetchContainer(h, gitjob)
+	if err != nil {
+		return nil, err
+	}
+
+	volumes := append([]corev1.Volume{
+		{
+			Name: gitClonerVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		},
+		{
+			Name: emptyDirVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		},
+	}, fetcherVolumes...)
+
+	volumeMounts := append([]corev1.VolumeMount{
+		{
+			Name:      gitClonerVolumeName,
+			MountPath: "/workspace",
+		},
+		{
+			Name:      emptyDirVolumeName,
+			MountPath: "/tmp",
+		},
+	}, fetcherMounts...)
+
+	initContainer := corev1.Container{
+		Command:      command,
+		Args:         args,
+		Image:        h.image,
+		Name:         "gitcloner-initializer",
+		VolumeMounts: volumeMounts,
+	}
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      gitjob.Name,
+			Namespace: gitjob.Namespace,
+		},
+		Spec: gitjob.Spec.JobSpec,
+	}
+	job.Spec.Template.Spec.InitContainers = append([]corev1.Container{initContainer}, job.Spec.Template.Spec.InitContainers...)
+	job.Spec.Template.Spec.Volumes = append(volumes, job.Spec.Template.Spec.Volumes...)
+
+	podSecurity := gitjob.Spec.PodSecurity
+	if podSecurity == "" {
+		podSecurity = v1.PodSecurityRestricted
+	}
+	if err := applyPodSecurity(job, podSecurity); err != nil {
+		return nil, err
+	}
+
+	if err := h.applyImagePullSecrets(job, gitjob.Namespace); err != nil {
+		return nil, err
+	}
+
+	setEnvVars(job, gitjob)
+
+	return job, nil
+}
+
+// gitCloneContainer builds the gitcloner command/args and any extra
+// volumes/volumeMounts needed to clone gitInfo, resolving its credential
+// via the secrets cache when it references a secret.
+func gitCloneContainer(h *Handler, namespace, name string, gitInfo v1.GitInfo) ([]string, []string, []corev1.Volume, []corev1.VolumeMount, error) {
+	args := []string{gitInfo.Repo, "/workspace"}
+
+	credArgs, volumes, mounts, err := credentialArgs(h, namespace, gitInfo.Credential)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	args = append(args, credArgs...)
+
+	tArgs, tVolumes, tMounts := tlsArgs(name, gitInfo.Credential)
+	args = append(args, tArgs...)
+	volumes = append(volumes, tVolumes...)
+	mounts = append(mounts, tMounts...)
+
+	args = append(args, cloneDepthArgs(gitInfo)...)
+
+	return []string{"gitcloner"}, args, volumes, mounts, nil
+}
+
+// cloneDepthArgs translates gitInfo's shallow-clone, submodule and
+// partial-clone settings into gitcloner flags, trimming clone time and disk
+// usage for large repositories.
+func cloneDepthArgs(gitInfo v1.GitInfo) []string {
+	var args []string
+
+	if gitInfo.Depth > 0 {
+		args = append(args, "--depth", strconv.FormatInt(int64(gitInfo.Depth), 10))
+	}
+	if gitInfo.SingleBranch {
+		args = append(args, "--single-branch")
+	}
+	switch gitInfo.Submodules {
+	case v1.SubmodulesShallow:
+		args = append(args, "--recurse-submodules", "--shallow-submodules")
+	case v1.SubmodulesRecursive:
+		args = append(args, "--recurse-submodules")
+	}
+	if gitInfo.PartialCloneFilter != "" {
+		args = append(args, "--filter", gitInfo.PartialCloneFilter)
+	}
+
+	return args
+}
+
+// credentialArgs translates credential into the flags and volumes/mounts a
+// fetcher needs to authenticate, independent of which resource it is
+// fetching. It does not handle CABundle/InsecureSkipTLSverify; every fetcher
+// combines it with tlsArgs for those.
+func credentialArgs(h *Handler, namespace string, credential v1.Credential) ([]string, []corev1.Volume, []corev1.VolumeMount, error) {
+	switch {
+	case credential.GitHubApp != nil:
+		args, volume, mount := githubAppCredential(credential.GitHubApp)
+		return args, []corev1.Volume{volume}, []corev1.VolumeMount{mount}, nil
+	case credential.OIDC != nil:
+		args, volume, mount := oidcCredential(credential.OIDC)
+		return args, []corev1.Volume{volume}, []corev1.VolumeMount{mount}, nil
+	case credential.ClientSecretName != "":
+		args, volume, mount, err := clientSecretCredential(h, namespace, credential.ClientSecretName)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		return args, []corev1.Volume{volume}, []corev1.VolumeMount{mount}, nil
+	default:
+		return nil, nil, nil, nil
+	}
+}
+
+// tlsArgs translates credential's CABundle/InsecureSkipTLSverify into the
+// flags and volume/mount any fetcher needs to reach a self-signed or
+// TLS-relaxed endpoint, independent of which resource it is fetching. name
+// is used to derive the CA bundle Secret's name, matching the GitJob it
+// belongs to.
+func tlsArgs(name string, credential v1.Credential) ([]string, []corev1.Volume, []corev1.VolumeMount) {
+	var args []string
+	var volumes []corev1.Volume
+	var mounts []corev1.VolumeMount
+
+	if len(credential.CABundle) > 0 {
+		args = append(args, "--ca-bundle-file", "/gitjob/cabundle/"+bundleCAFile)
+		mounts = append(mounts, corev1.VolumeMount{
+			Name:      bundleCAVolumeName,
+			MountPath: "/gitjob/cabundle",
+		})
+		volumes = append(volumes, corev1.Volume{
+			Name: bundleCAVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: fmt.Sprintf("%s-cabundle", name),
+				},
+			},
+		})
+	}
+
+	if credential.InsecureSkipTLSverify {
+		args = append(args, "--insecure-skip-tls")
+	}
+
+	return args, volumes, mounts
+}
+
+// clientSecretCredential resolves secretName and translates it into
+// gitcloner args, depending on whether it is a basic-auth or SSH secret.
+func clientSecretCredential(h *Handler, namespace, secretName string) ([]string, corev1.Volume, corev1.VolumeMount, error) {
+	secret, err := h.secrets.Get(namespace, secretName)
+	if err != nil {
+		return nil, corev1.Volume{}, corev1.VolumeMount{}, err
+	}
+
+	volume := corev1.Volume{
+		Name: gitCredentialVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: secretName,
+			},
+		},
+	}
+
+	switch secret.Type {
+	case corev1.SecretTypeSSHAuth:
+		mount := corev1.VolumeMount{
+			Name:      gitCredentialVolumeName,
+			MountPath: "/gitjob/ssh",
+		}
+		args := []string{"--ssh-private-key-file", "/gitjob/ssh/" + corev1.SSHAuthPrivateKey}
+		if _, ok := secret.Data[knownHostsSecretKey]; ok {
+			args = append(args, "--known-hosts-file", "/gitjob/ssh/"+knownHostsSecretKey)
+		}
+		return args, volume, mount, nil
+	default:
+		mount := corev1.VolumeMount{
+			Name:      gitCredentialVolumeName,
+			MountPath: "/gitjob/credentials",
+		}
+		args := []string{
+			"--username", string(secret.Data[corev1.BasicAuthUsernameKey]),
+			"--password-file", "/gitjob/credentials/" + corev1.BasicAuthPasswordKey,
+		}
+		return args, volume, mount, nil
+	}
+}
+
+// githubAppCredential mounts the GitHub App's private key and passes the
+// App/installation identifiers so the fetcher can mint a short-lived
+// installation access token at runtime.
+func githubAppCredential(app *v1.GitHubAppCredential) ([]string, corev1.Volume, corev1.VolumeMount) {
+	mount := corev1.VolumeMount{
+		Name:      githubAppVolumeName,
+		MountPath: "/gitjob/github-app",
+	}
+	volume := corev1.Volume{
+		Name: githubAppVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: app.PrivateKeySecretRef.Name,
+				Items: []corev1.KeyToPath{
+					{Key: app.PrivateKeySecretRef.Key, Path: app.PrivateKeySecretRef.Key},
+				},
+			},
+		},
+	}
+	args := []string{
+		"--github-app-id", strconv.FormatInt(app.AppID, 10),
+		"--github-app-installation-id", strconv.FormatInt(app.InstallationID, 10),
+		"--github-app-private-key-file", "/gitjob/github-app/" + app.PrivateKeySecretRef.Key,
+	}
+	return args, volume, mount
+}
+
+// oidcCredential projects a ServiceAccountToken for audience so the fetcher
+// can exchange it for a short-lived provider token to use as the HTTP
+// basic-auth password when fetching.
+func oidcCredential(oidc *v1.OIDCCredential) ([]string, corev1.Volume, corev1.VolumeMount) {
+	mount := corev1.VolumeMount{
+		Name:      oidcTokenVolumeName,
+		MountPath: "/var/run/secrets/gitjob/oidc",
+	}
+	volume := corev1.Volume{
+		Name: oidcTokenVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Projected: &corev1.ProjectedVolumeSource{
+				Sources: []corev1.VolumeProjection{
+					{
+						ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+							Audience: oidc.Audience,
+							Path:     oidcTokenFile,
+						},
+					},
+				},
+			},
+		},
+	}
+	args := []string{
+		"--oidc-token-file", "/var/run/secrets/gitjob/oidc/" + oidcTokenFile,
+		"--oidc-audience", oidc.Audience,
+	}
+	return args, volume, mount
+}
+
+// setEnvVars injects the commit/event metadata that triggered this Job into
+// the user-supplied container, and forwards the controller's own proxy
+// configuration into both the user container and the init container.
+func setEnvVars(job *batchv1.Job, gitjob *v1.GitJob) {
+	containers := job.Spec.Template.Spec.Containers
+	if len(containers) > 0 {
+		containers[0].Env = append(containers[0].Env,
+			corev1.EnvVar{Name: commitEnvVar, Value: gitjob.Status.GitEvent.Commit},
+			corev1.EnvVar{Name: eventTypeEnvVar, Value: gitjob.Status.GitEvent.GithubMeta.Event},
+		)
+	}
+
+	var proxyEnv []corev1.EnvVar
+	if v := os.Getenv(httpProxyEnvVar); v != "" {
+		proxyEnv = append(proxyEnv, corev1.EnvVar{Name: httpProxyEnvVar, Value: v})
+	}
+	if v := os.Getenv(httpsProxyEnvVar); v != "" {
+		proxyEnv = append(proxyEnv, corev1.EnvVar{Name: httpsProxyEnvVar, Value: v})
+	}
+	if len(proxyEnv) == 0 {
+		return
+	}
+
+	if len(containers) > 0 {
+		containers[0].Env = append(containers[0].Env, proxyEnv...)
+	}
+	initContainers := job.Spec.Template.Spec.InitContainers
+	if len(initContainers) > 0 {
+		initContainers[0].Env = append(initContainers[0].Env, proxyEnv...)
+	}
+}
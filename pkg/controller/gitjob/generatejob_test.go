@@ -2,6 +2,7 @@ package gitjob
 
 import (
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/golang/mock/gomock"
@@ -14,19 +15,25 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// restrictedSecurityContextFixture is the SecurityContext every container
+// gets under v1.PodSecurityRestricted, gitjob's default level. It's shared by
+// every test below that exercises the default PodSecurity behavior so the
+// expectation can't drift between them.
+var restrictedSecurityContextFixture = &corev1.SecurityContext{
+	AllowPrivilegeEscalation: &[]bool{false}[0],
+	ReadOnlyRootFilesystem:   &[]bool{true}[0],
+	Privileged:               &[]bool{false}[0],
+	Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+	RunAsNonRoot:             &[]bool{true}[0],
+	SeccompProfile: &corev1.SeccompProfile{
+		Type: corev1.SeccompProfileTypeRuntimeDefault,
+	},
+}
+
 func TestGenerateJob(t *testing.T) {
 	ctrl := gomock.NewController(t)
 
-	securityContext := &corev1.SecurityContext{
-		AllowPrivilegeEscalation: &[]bool{false}[0],
-		ReadOnlyRootFilesystem:   &[]bool{true}[0],
-		Privileged:               &[]bool{false}[0],
-		Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
-		RunAsNonRoot:             &[]bool{true}[0],
-		SeccompProfile: &corev1.SeccompProfile{
-			Type: corev1.SeccompProfileTypeRuntimeDefault,
-		},
-	}
+	securityContext := restrictedSecurityContextFixture
 
 	tests := map[string]struct {
 		gitjob                 *v1.GitJob
@@ -195,6 +202,70 @@ func TestGenerateJob(t *testing.T) {
 			},
 			secret: sshSecretMock(ctrl),
 		},
+		"ssh credentials with known_hosts": {
+			gitjob: &v1.GitJob{
+				Spec: v1.GitJobSpec{
+					Git: v1.GitInfo{
+						Repo: "repo",
+						Credential: v1.Credential{
+							ClientSecretName: "secretName",
+						},
+					},
+				},
+			},
+			expectedInitContainers: []corev1.Container{
+				{
+					Command: []string{
+						"gitcloner",
+					},
+					Args: []string{
+						"repo", "/workspace",
+						"--ssh-private-key-file", "/gitjob/ssh/" + corev1.SSHAuthPrivateKey,
+						"--known-hosts-file", "/gitjob/ssh/" + knownHostsSecretKey,
+					},
+					Image: "test",
+					Name:  "gitcloner-initializer",
+					VolumeMounts: []corev1.VolumeMount{
+						{
+							Name:      gitClonerVolumeName,
+							MountPath: "/workspace",
+						},
+						{
+							Name:      emptyDirVolumeName,
+							MountPath: "/tmp",
+						},
+						{
+							Name:      gitCredentialVolumeName,
+							MountPath: "/gitjob/ssh",
+						},
+					},
+					SecurityContext: securityContext,
+				},
+			},
+			expectedVolumes: []corev1.Volume{
+				{
+					Name: gitClonerVolumeName,
+					VolumeSource: corev1.VolumeSource{
+						EmptyDir: &corev1.EmptyDirVolumeSource{},
+					},
+				},
+				{
+					Name: emptyDirVolumeName,
+					VolumeSource: corev1.VolumeSource{
+						EmptyDir: &corev1.EmptyDirVolumeSource{},
+					},
+				},
+				{
+					Name: gitCredentialVolumeName,
+					VolumeSource: corev1.VolumeSource{
+						Secret: &corev1.SecretVolumeSource{
+							SecretName: "secretName",
+						},
+					},
+				},
+			},
+			secret: sshKnownHostsSecretMock(ctrl),
+		},
 		"custom CA": {
 			gitjob: &v1.GitJob{
 				Spec: v1.GitJobSpec{
@@ -301,6 +372,143 @@ func TestGenerateJob(t *testing.T) {
 				},
 			},
 		},
+		"github app credentials": {
+			gitjob: &v1.GitJob{
+				Spec: v1.GitJobSpec{
+					Git: v1.GitInfo{
+						Repo: "repo",
+						Credential: v1.Credential{
+							GitHubApp: &v1.GitHubAppCredential{
+								AppID:          123,
+								InstallationID: 456,
+								PrivateKeySecretRef: corev1.SecretKeySelector{
+									LocalObjectReference: corev1.LocalObjectReference{Name: "ghAppSecret"},
+									Key:                  "privateKey",
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedInitContainers: []corev1.Container{
+				{
+					Command: []string{
+						"gitcloner",
+					},
+					Args:  []string{"repo", "/workspace", "--github-app-id", "123", "--github-app-installation-id", "456", "--github-app-private-key-file", "/gitjob/github-app/privateKey"},
+					Image: "test",
+					Name:  "gitcloner-initializer",
+					VolumeMounts: []corev1.VolumeMount{
+						{
+							Name:      gitClonerVolumeName,
+							MountPath: "/workspace",
+						},
+						{
+							Name:      emptyDirVolumeName,
+							MountPath: "/tmp",
+						},
+						{
+							Name:      githubAppVolumeName,
+							MountPath: "/gitjob/github-app",
+						},
+					},
+					SecurityContext: securityContext,
+				},
+			},
+			expectedVolumes: []corev1.Volume{
+				{
+					Name: gitClonerVolumeName,
+					VolumeSource: corev1.VolumeSource{
+						EmptyDir: &corev1.EmptyDirVolumeSource{},
+					},
+				},
+				{
+					Name: emptyDirVolumeName,
+					VolumeSource: corev1.VolumeSource{
+						EmptyDir: &corev1.EmptyDirVolumeSource{},
+					},
+				},
+				{
+					Name: githubAppVolumeName,
+					VolumeSource: corev1.VolumeSource{
+						Secret: &corev1.SecretVolumeSource{
+							SecretName: "ghAppSecret",
+							Items: []corev1.KeyToPath{
+								{Key: "privateKey", Path: "privateKey"},
+							},
+						},
+					},
+				},
+			},
+		},
+		"oidc credentials": {
+			gitjob: &v1.GitJob{
+				Spec: v1.GitJobSpec{
+					Git: v1.GitInfo{
+						Repo: "repo",
+						Credential: v1.Credential{
+							OIDC: &v1.OIDCCredential{
+								Audience: "sts.amazonaws.com",
+							},
+						},
+					},
+				},
+			},
+			expectedInitContainers: []corev1.Container{
+				{
+					Command: []string{
+						"gitcloner",
+					},
+					Args:  []string{"repo", "/workspace", "--oidc-token-file", "/var/run/secrets/gitjob/oidc/token", "--oidc-audience", "sts.amazonaws.com"},
+					Image: "test",
+					Name:  "gitcloner-initializer",
+					VolumeMounts: []corev1.VolumeMount{
+						{
+							Name:      gitClonerVolumeName,
+							MountPath: "/workspace",
+						},
+						{
+							Name:      emptyDirVolumeName,
+							MountPath: "/tmp",
+						},
+						{
+							Name:      oidcTokenVolumeName,
+							MountPath: "/var/run/secrets/gitjob/oidc",
+						},
+					},
+					SecurityContext: securityContext,
+				},
+			},
+			expectedVolumes: []corev1.Volume{
+				{
+					Name: gitClonerVolumeName,
+					VolumeSource: corev1.VolumeSource{
+						EmptyDir: &corev1.EmptyDirVolumeSource{},
+					},
+				},
+				{
+					Name: emptyDirVolumeName,
+					VolumeSource: corev1.VolumeSource{
+						EmptyDir: &corev1.EmptyDirVolumeSource{},
+					},
+				},
+				{
+					Name: oidcTokenVolumeName,
+					VolumeSource: corev1.VolumeSource{
+						Projected: &corev1.ProjectedVolumeSource{
+							Sources: []corev1.VolumeProjection{
+								{
+									ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+										Audience: "sts.amazonaws.com",
+										Path:     "token",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for name, test := range tests {
@@ -491,4 +699,697 @@ func sshSecretMock(ctrl *gomock.Controller) corev1controller.SecretCache {
 	}, nil)
 
 	return secretmock
-}
\ No newline at end of file
+}
+
+func sshKnownHostsSecretMock(ctrl *gomock.Controller) corev1controller.SecretCache {
+	secretmock := mocks.NewMockSecretCache(ctrl)
+	secretmock.EXPECT().Get(gomock.Any(), gomock.Any()).Return(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{},
+		Data: map[string][]byte{
+			corev1.SSHAuthPrivateKey: []byte("ssh key"),
+			knownHostsSecretKey:      []byte("github.com ssh-rsa AAAA..."),
+		},
+		Type: corev1.SecretTypeSSHAuth,
+	}, nil)
+
+	return secretmock
+}
+
+func TestGenerateJob_Sources(t *testing.T) {
+	securityContext := restrictedSecurityContextFixture
+
+	tests := map[string]struct {
+		gitjob                 *v1.GitJob
+		expectedInitContainers []corev1.Container
+		expectedVolumes        []corev1.Volume
+		expectedErr            bool
+	}{
+		"oci source": {
+			gitjob: &v1.GitJob{
+				Spec: v1.GitJobSpec{
+					Source: v1.Source{
+						Type: v1.SourceTypeOCI,
+						OCI: &v1.OCISource{
+							Repo: "example.com/charts/app",
+							Tag:  "v1.0.0",
+						},
+					},
+				},
+			},
+			expectedInitContainers: []corev1.Container{
+				{
+					Command: []string{"ocicloner"},
+					Args:    []string{"example.com/charts/app:v1.0.0", "/workspace"},
+					Image:   "test",
+					Name:    "gitcloner-initializer",
+					VolumeMounts: []corev1.VolumeMount{
+						{Name: gitClonerVolumeName, MountPath: "/workspace"},
+						{Name: emptyDirVolumeName, MountPath: "/tmp"},
+					},
+					SecurityContext: securityContext,
+				},
+			},
+			expectedVolumes: []corev1.Volume{
+				{Name: gitClonerVolumeName, VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+				{Name: emptyDirVolumeName, VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+			},
+		},
+		"oci source with caBundle and insecure skip tls": {
+			gitjob: &v1.GitJob{
+				ObjectMeta: metav1.ObjectMeta{Name: "myjob"},
+				Spec: v1.GitJobSpec{
+					Source: v1.Source{
+						Type: v1.SourceTypeOCI,
+						OCI: &v1.OCISource{
+							Repo: "example.com/charts/app",
+							Tag:  "v1.0.0",
+							Credential: v1.Credential{
+								CABundle:              []byte("ca"),
+								InsecureSkipTLSverify: true,
+							},
+						},
+					},
+				},
+			},
+			expectedInitContainers: []corev1.Container{
+				{
+					Command: []string{"ocicloner"},
+					Args: []string{
+						"example.com/charts/app:v1.0.0", "/workspace",
+						"--ca-bundle-file", "/gitjob/cabundle/" + bundleCAFile,
+						"--insecure-skip-tls",
+					},
+					Image: "test",
+					Name:  "gitcloner-initializer",
+					VolumeMounts: []corev1.VolumeMount{
+						{Name: gitClonerVolumeName, MountPath: "/workspace"},
+						{Name: emptyDirVolumeName, MountPath: "/tmp"},
+						{Name: bundleCAVolumeName, MountPath: "/gitjob/cabundle"},
+					},
+					SecurityContext: securityContext,
+				},
+			},
+			expectedVolumes: []corev1.Volume{
+				{Name: gitClonerVolumeName, VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+				{Name: emptyDirVolumeName, VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+				{
+					Name: bundleCAVolumeName,
+					VolumeSource: corev1.VolumeSource{
+						Secret: &corev1.SecretVolumeSource{SecretName: "myjob-cabundle"},
+					},
+				},
+			},
+		},
+		"http source": {
+			gitjob: &v1.GitJob{
+				Spec: v1.GitJobSpec{
+					Source: v1.Source{
+						Type: v1.SourceTypeHTTP,
+						HTTP: &v1.HTTPSource{
+							URL: "https://example.com/archive.tar.gz",
+						},
+					},
+				},
+			},
+			expectedInitContainers: []corev1.Container{
+				{
+					Command: []string{"httpfetcher"},
+					Args:    []string{"https://example.com/archive.tar.gz", "/workspace"},
+					Image:   "test",
+					Name:    "gitcloner-initializer",
+					VolumeMounts: []corev1.VolumeMount{
+						{Name: gitClonerVolumeName, MountPath: "/workspace"},
+						{Name: emptyDirVolumeName, MountPath: "/tmp"},
+					},
+					SecurityContext: securityContext,
+				},
+			},
+			expectedVolumes: []corev1.Volume{
+				{Name: gitClonerVolumeName, VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+				{Name: emptyDirVolumeName, VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+			},
+		},
+		"http source with insecure skip tls": {
+			gitjob: &v1.GitJob{
+				Spec: v1.GitJobSpec{
+					Source: v1.Source{
+						Type: v1.SourceTypeHTTP,
+						HTTP: &v1.HTTPSource{
+							URL: "https://example.com/archive.tar.gz",
+							Credential: v1.Credential{
+								InsecureSkipTLSverify: true,
+							},
+						},
+					},
+				},
+			},
+			expectedInitContainers: []corev1.Container{
+				{
+					Command: []string{"httpfetcher"},
+					Args:    []string{"https://example.com/archive.tar.gz", "/workspace", "--insecure-skip-tls"},
+					Image:   "test",
+					Name:    "gitcloner-initializer",
+					VolumeMounts: []corev1.VolumeMount{
+						{Name: gitClonerVolumeName, MountPath: "/workspace"},
+						{Name: emptyDirVolumeName, MountPath: "/tmp"},
+					},
+					SecurityContext: securityContext,
+				},
+			},
+			expectedVolumes: []corev1.Volume{
+				{Name: gitClonerVolumeName, VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+				{Name: emptyDirVolumeName, VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+			},
+		},
+		"gcs source": {
+			gitjob: &v1.GitJob{
+				Spec: v1.GitJobSpec{
+					Source: v1.Source{
+						Type: v1.SourceTypeGCS,
+						GCS: &v1.GCSSource{
+							Bucket: "bucket",
+							Object: "path/to/object.tar.gz",
+						},
+					},
+				},
+			},
+			expectedInitContainers: []corev1.Container{
+				{
+					Command: []string{"gcsfetcher"},
+					Args:    []string{"gs://bucket/path/to/object.tar.gz", "/workspace"},
+					Image:   "test",
+					Name:    "gitcloner-initializer",
+					VolumeMounts: []corev1.VolumeMount{
+						{Name: gitClonerVolumeName, MountPath: "/workspace"},
+						{Name: emptyDirVolumeName, MountPath: "/tmp"},
+					},
+					SecurityContext: securityContext,
+				},
+			},
+			expectedVolumes: []corev1.Volume{
+				{Name: gitClonerVolumeName, VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+				{Name: emptyDirVolumeName, VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+			},
+		},
+		"s3 source": {
+			gitjob: &v1.GitJob{
+				Spec: v1.GitJobSpec{
+					Source: v1.Source{
+						Type: v1.SourceTypeS3,
+						S3: &v1.S3Source{
+							Bucket: "bucket",
+							Key:    "path/to/object.tar.gz",
+							Region: "us-east-1",
+						},
+					},
+				},
+			},
+			expectedInitContainers: []corev1.Container{
+				{
+					Command: []string{"s3fetcher"},
+					Args:    []string{"s3://bucket/path/to/object.tar.gz", "/workspace", "--region", "us-east-1"},
+					Image:   "test",
+					Name:    "gitcloner-initializer",
+					VolumeMounts: []corev1.VolumeMount{
+						{Name: gitClonerVolumeName, MountPath: "/workspace"},
+						{Name: emptyDirVolumeName, MountPath: "/tmp"},
+					},
+					SecurityContext: securityContext,
+				},
+			},
+			expectedVolumes: []corev1.Volume{
+				{Name: gitClonerVolumeName, VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+				{Name: emptyDirVolumeName, VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+			},
+		},
+		"unknown source type": {
+			gitjob: &v1.GitJob{
+				Spec: v1.GitJobSpec{
+					Source: v1.Source{
+						Type: "bogus",
+					},
+				},
+			},
+			expectedErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			h := Handler{image: "test"}
+			job, err := h.generateJob(test.gitjob)
+			if test.expectedErr {
+				if err == nil {
+					t.Fatalf("expected an error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !cmp.Equal(job.Spec.Template.Spec.InitContainers, test.expectedInitContainers) {
+				t.Fatalf("expected initContainers: %v, got: %v", test.expectedInitContainers, job.Spec.Template.Spec.InitContainers)
+			}
+			if !cmp.Equal(job.Spec.Template.Spec.Volumes, test.expectedVolumes) {
+				t.Fatalf("expected volumes: %v, got: %v", test.expectedVolumes, job.Spec.Template.Spec.Volumes)
+			}
+		})
+	}
+}
+
+func TestGenerateJob_PodSecurity(t *testing.T) {
+	restrictedSC := restrictedSecurityContextFixture
+	baselineSC := &corev1.SecurityContext{
+		AllowPrivilegeEscalation: &[]bool{false}[0],
+		Privileged:               &[]bool{false}[0],
+		Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+	}
+
+	tests := map[string]struct {
+		gitjob                      *v1.GitJob
+		expectedInitSecurityContext *corev1.SecurityContext
+		expectedUserSecurityContext *corev1.SecurityContext
+		expectedErr                 bool
+	}{
+		"default is restricted": {
+			gitjob: &v1.GitJob{
+				Spec: v1.GitJobSpec{
+					Git: v1.GitInfo{Repo: "repo"},
+					JobSpec: batchv1.JobSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{{Name: "user"}},
+							},
+						},
+					},
+				},
+			},
+			expectedInitSecurityContext: restrictedSC,
+			expectedUserSecurityContext: restrictedSC,
+		},
+		"restricted": {
+			gitjob: &v1.GitJob{
+				Spec: v1.GitJobSpec{
+					Git:         v1.GitInfo{Repo: "repo"},
+					PodSecurity: v1.PodSecurityRestricted,
+					JobSpec: batchv1.JobSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{{Name: "user"}},
+							},
+						},
+					},
+				},
+			},
+			expectedInitSecurityContext: restrictedSC,
+			expectedUserSecurityContext: restrictedSC,
+		},
+		"baseline": {
+			gitjob: &v1.GitJob{
+				Spec: v1.GitJobSpec{
+					Git:         v1.GitInfo{Repo: "repo"},
+					PodSecurity: v1.PodSecurityBaseline,
+					JobSpec: batchv1.JobSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{{Name: "user"}},
+							},
+						},
+					},
+				},
+			},
+			expectedInitSecurityContext: baselineSC,
+			expectedUserSecurityContext: baselineSC,
+		},
+		"restricted preserves a user-set RunAsUser": {
+			gitjob: &v1.GitJob{
+				Spec: v1.GitJobSpec{
+					Git: v1.GitInfo{Repo: "repo"},
+					JobSpec: batchv1.JobSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{
+									{
+										Name: "user",
+										SecurityContext: &corev1.SecurityContext{
+											RunAsUser: &[]int64{1000}[0],
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedInitSecurityContext: restrictedSC,
+			expectedUserSecurityContext: &corev1.SecurityContext{
+				AllowPrivilegeEscalation: &[]bool{false}[0],
+				ReadOnlyRootFilesystem:   &[]bool{true}[0],
+				Privileged:               &[]bool{false}[0],
+				Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+				RunAsNonRoot:             &[]bool{true}[0],
+				RunAsUser:                &[]int64{1000}[0],
+				SeccompProfile: &corev1.SeccompProfile{
+					Type: corev1.SeccompProfileTypeRuntimeDefault,
+				},
+			},
+		},
+		"restricted overrides a non-compliant pre-set SeccompProfile": {
+			gitjob: &v1.GitJob{
+				Spec: v1.GitJobSpec{
+					Git: v1.GitInfo{Repo: "repo"},
+					JobSpec: batchv1.JobSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{
+									{
+										Name: "user",
+										SecurityContext: &corev1.SecurityContext{
+											SeccompProfile: &corev1.SeccompProfile{
+												Type: corev1.SeccompProfileTypeUnconfined,
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedInitSecurityContext: restrictedSC,
+			expectedUserSecurityContext: restrictedSC,
+		},
+		"privileged leaves containers untouched": {
+			gitjob: &v1.GitJob{
+				Spec: v1.GitJobSpec{
+					Git:         v1.GitInfo{Repo: "repo"},
+					PodSecurity: v1.PodSecurityPrivileged,
+					JobSpec: batchv1.JobSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{{Name: "user"}},
+							},
+						},
+					},
+				},
+			},
+		},
+		"restricted rejects hostNetwork": {
+			gitjob: &v1.GitJob{
+				Spec: v1.GitJobSpec{
+					Git: v1.GitInfo{Repo: "repo"},
+					JobSpec: batchv1.JobSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								HostNetwork: true,
+							},
+						},
+					},
+				},
+			},
+			expectedErr: true,
+		},
+		"restricted rejects hostPath volumes": {
+			gitjob: &v1.GitJob{
+				Spec: v1.GitJobSpec{
+					Git: v1.GitInfo{Repo: "repo"},
+					JobSpec: batchv1.JobSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Volumes: []corev1.Volume{
+									{Name: "host", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/etc"}}},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedErr: true,
+		},
+		"privileged allows hostNetwork": {
+			gitjob: &v1.GitJob{
+				Spec: v1.GitJobSpec{
+					Git:         v1.GitInfo{Repo: "repo"},
+					PodSecurity: v1.PodSecurityPrivileged,
+					JobSpec: batchv1.JobSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								HostNetwork: true,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			h := Handler{image: "test"}
+			job, err := h.generateJob(test.gitjob)
+			if test.expectedErr {
+				if err == nil {
+					t.Fatalf("expected an error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !cmp.Equal(job.Spec.Template.Spec.InitContainers[0].SecurityContext, test.expectedInitSecurityContext) {
+				t.Fatalf("expected init SecurityContext: %v, got: %v", test.expectedInitSecurityContext, job.Spec.Template.Spec.InitContainers[0].SecurityContext)
+			}
+			if len(job.Spec.Template.Spec.Containers) > 0 {
+				if !cmp.Equal(job.Spec.Template.Spec.Containers[0].SecurityContext, test.expectedUserSecurityContext) {
+					t.Fatalf("expected user SecurityContext: %v, got: %v", test.expectedUserSecurityContext, job.Spec.Template.Spec.Containers[0].SecurityContext)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateJob_ImagePullSecrets(t *testing.T) {
+	tests := map[string]struct {
+		gitjob                   *v1.GitJob
+		registryCredentials      []RegistryCredential
+		expectedImagePullSecrets []corev1.LocalObjectReference
+	}{
+		"no registry credentials configured": {
+			gitjob: &v1.GitJob{
+				Spec: v1.GitJobSpec{Git: v1.GitInfo{Repo: "repo"}},
+			},
+		},
+		"init container image matches a private registry": {
+			gitjob: &v1.GitJob{
+				Spec: v1.GitJobSpec{Git: v1.GitInfo{Repo: "repo"}},
+			},
+			registryCredentials: []RegistryCredential{
+				{Host: "docker.io", Username: "user", Password: "pass"},
+			},
+			expectedImagePullSecrets: []corev1.LocalObjectReference{
+				{Name: "gitjob-pull-docker-io"},
+			},
+		},
+		"user container image matches a private registry": {
+			gitjob: &v1.GitJob{
+				Spec: v1.GitJobSpec{
+					Git: v1.GitInfo{Repo: "repo"},
+					JobSpec: batchv1.JobSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{
+									{Image: "registry.example.com/team/app:v1"},
+								},
+							},
+						},
+					},
+				},
+			},
+			registryCredentials: []RegistryCredential{
+				{Host: "registry.example.com", Username: "user", Password: "pass"},
+			},
+			expectedImagePullSecrets: []corev1.LocalObjectReference{
+				{Name: "gitjob-pull-registry-example-com"},
+			},
+		},
+		"image without a matching registry credential is left alone": {
+			gitjob: &v1.GitJob{
+				Spec: v1.GitJobSpec{
+					Git: v1.GitInfo{Repo: "repo"},
+					JobSpec: batchv1.JobSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{
+									{Image: "public.example.com/app:v1"},
+								},
+							},
+						},
+					},
+				},
+			},
+			registryCredentials: []RegistryCredential{
+				{Host: "registry.example.com", Username: "user", Password: "pass"},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			h := Handler{image: "test", registryCredentials: test.registryCredentials}
+			job, err := h.generateJob(test.gitjob)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !cmp.Equal(job.Spec.Template.Spec.ImagePullSecrets, test.expectedImagePullSecrets) {
+				t.Fatalf("expected imagePullSecrets: %v, got: %v", test.expectedImagePullSecrets, job.Spec.Template.Spec.ImagePullSecrets)
+			}
+		})
+	}
+}
+
+// TestGenerateJob_ImagePullSecrets_Rotation asserts that a rotated
+// RegistryCredential password overwrites the previously synthesized
+// dockerconfigjson Secret instead of being silently dropped once the Secret
+// already exists.
+func TestGenerateJob_ImagePullSecrets_Rotation(t *testing.T) {
+	client := &fakeSecretClient{}
+	gitjob := &v1.GitJob{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns"},
+		Spec:       v1.GitJobSpec{Git: v1.GitInfo{Repo: "repo"}},
+	}
+
+	h := Handler{
+		image:        "test",
+		secretClient: client,
+		registryCredentials: []RegistryCredential{
+			{Host: "docker.io", Username: "user", Password: "old-pass"},
+		},
+	}
+	if _, err := h.generateJob(gitjob); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	secret, err := client.Get("ns", "gitjob-pull-docker-io", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected the pull secret to have been created: %v", err)
+	}
+	if strings.Contains(string(secret.Data[corev1.DockerConfigJsonKey]), "new-pass") {
+		t.Fatalf("did not expect the rotated password before rotation, got: %q", secret.Data[corev1.DockerConfigJsonKey])
+	}
+
+	h.registryCredentials = []RegistryCredential{
+		{Host: "docker.io", Username: "user", Password: "new-pass"},
+	}
+	if _, err := h.generateJob(gitjob); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	secret, err = client.Get("ns", "gitjob-pull-docker-io", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected the pull secret to still exist: %v", err)
+	}
+	data := string(secret.Data[corev1.DockerConfigJsonKey])
+	if strings.Contains(data, "old-pass") {
+		t.Fatalf("expected the rotated password to replace the old one, got: %q", data)
+	}
+	if !strings.Contains(data, "new-pass") {
+		t.Fatalf("expected the stored secret to reflect the rotated password, got: %q", data)
+	}
+}
+
+func TestGenerateJob_CloneDepthArgs(t *testing.T) {
+	tests := map[string]struct {
+		gitInfo      v1.GitInfo
+		expectedArgs []string
+	}{
+		"no shallow/submodule/partial-clone settings": {
+			gitInfo:      v1.GitInfo{Repo: "repo"},
+			expectedArgs: nil,
+		},
+		"depth only": {
+			gitInfo:      v1.GitInfo{Repo: "repo", Depth: 1},
+			expectedArgs: []string{"--depth", "1"},
+		},
+		"single branch only": {
+			gitInfo:      v1.GitInfo{Repo: "repo", SingleBranch: true},
+			expectedArgs: []string{"--single-branch"},
+		},
+		"shallow submodules": {
+			gitInfo:      v1.GitInfo{Repo: "repo", Submodules: v1.SubmodulesShallow},
+			expectedArgs: []string{"--recurse-submodules", "--shallow-submodules"},
+		},
+		"recursive submodules": {
+			gitInfo:      v1.GitInfo{Repo: "repo", Submodules: v1.SubmodulesRecursive},
+			expectedArgs: []string{"--recurse-submodules"},
+		},
+		"submodules none is a no-op": {
+			gitInfo:      v1.GitInfo{Repo: "repo", Submodules: v1.SubmodulesNone},
+			expectedArgs: nil,
+		},
+		"partial clone filter only": {
+			gitInfo:      v1.GitInfo{Repo: "repo", PartialCloneFilter: "blob:none"},
+			expectedArgs: []string{"--filter", "blob:none"},
+		},
+		"depth, single branch, recursive submodules and partial clone combined": {
+			gitInfo: v1.GitInfo{
+				Repo:               "repo",
+				Depth:              10,
+				SingleBranch:       true,
+				Submodules:         v1.SubmodulesRecursive,
+				PartialCloneFilter: "tree:0",
+			},
+			expectedArgs: []string{
+				"--depth", "10",
+				"--single-branch",
+				"--recurse-submodules",
+				"--filter", "tree:0",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			args := cloneDepthArgs(test.gitInfo)
+			if !cmp.Equal(args, test.expectedArgs) {
+				t.Fatalf("expected args: %v, got: %v", test.expectedArgs, args)
+			}
+		})
+	}
+}
+
+func TestGenerateJob_CloneDepthWithCredentialsAndCA(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	gitjob := &v1.GitJob{
+		Spec: v1.GitJobSpec{
+			Git: v1.GitInfo{
+				Repo: "repo",
+				Credential: v1.Credential{
+					ClientSecretName: "secretName",
+					CABundle:         []byte("ca"),
+				},
+				Depth:        1,
+				SingleBranch: true,
+				Submodules:   v1.SubmodulesRecursive,
+			},
+		},
+	}
+
+	h := Handler{image: "test", secrets: httpSecretMock(ctrl)}
+	job, err := h.generateJob(gitjob)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedArgs := []string{
+		"repo", "/workspace",
+		"--username", "user", "--password-file", "/gitjob/credentials/" + corev1.BasicAuthPasswordKey,
+		"--ca-bundle-file", "/gitjob/cabundle/" + bundleCAFile,
+		"--depth", "1",
+		"--single-branch",
+		"--recurse-submodules",
+	}
+	if !cmp.Equal(job.Spec.Template.Spec.InitContainers[0].Args, expectedArgs) {
+		t.Fatalf("expected args: %v, got: %v", expectedArgs, job.Spec.Template.Spec.InitContainers[0].Args)
+	}
+}
@@ -0,0 +1,153 @@
+package gitjob
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RegistryCredential is a controller-level, statically configured
+// credential gitjob uses to synthesize an imagePullSecret for a private
+// registry, so cluster admins don't have to pre-create one in every
+// namespace a GitJob runs in.
+type RegistryCredential struct {
+	Host     string
+	Username string
+	Password string
+}
+
+// applyImagePullSecrets finds which of job's init and user container images
+// match one of h.registryCredentials by registry host, ensures a
+// kubernetes.io/dockerconfigjson Secret exists for each match, and attaches
+// it to the Pod's ImagePullSecrets.
+func (h *Handler) applyImagePullSecrets(job *batchv1.Job, namespace string) error {
+	if len(h.registryCredentials) == 0 {
+		return nil
+	}
+
+	spec := &job.Spec.Template.Spec
+	images := make([]string, 0, len(spec.InitContainers)+len(spec.Containers))
+	for _, c := range spec.InitContainers {
+		images = append(images, c.Image)
+	}
+	for _, c := range spec.Containers {
+		images = append(images, c.Image)
+	}
+
+	seen := map[string]bool{}
+	for _, image := range images {
+		cred, ok := matchRegistryCredential(h.registryCredentials, registryHost(image))
+		if !ok {
+			continue
+		}
+
+		secretName := pullSecretName(cred.Host)
+		if seen[secretName] {
+			continue
+		}
+		seen[secretName] = true
+
+		if err := h.ensurePullSecret(namespace, secretName, cred); err != nil {
+			return err
+		}
+		spec.ImagePullSecrets = append(spec.ImagePullSecrets, corev1.LocalObjectReference{Name: secretName})
+	}
+	return nil
+}
+
+// ensurePullSecret creates the dockerconfigjson Secret for cred, or updates
+// it in place if it already exists, so a rotated RegistryCredential password
+// actually propagates to every namespace that already has the Secret instead
+// of being silently ignored forever. It is a no-op when h.secretClient isn't
+// set, so generateJob can still be unit tested without a live secret client.
+func (h *Handler) ensurePullSecret(namespace, name string, cred RegistryCredential) error {
+	if h.secretClient == nil {
+		return nil
+	}
+
+	secret, err := dockerConfigSecret(namespace, name, cred)
+	if err != nil {
+		return err
+	}
+
+	if _, err := h.secretClient.Create(secret); err != nil {
+		if !apierrors.IsAlreadyExists(err) {
+			return err
+		}
+
+		existing, err := h.secretClient.Get(namespace, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		existing.Type = secret.Type
+		existing.Data = secret.Data
+		if _, err := h.secretClient.Update(existing); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func dockerConfigSecret(namespace, name string, cred RegistryCredential) (*corev1.Secret, error) {
+	auth := base64.StdEncoding.EncodeToString([]byte(cred.Username + ":" + cred.Password))
+	config := map[string]any{
+		"auths": map[string]any{
+			cred.Host: map[string]string{
+				"username": cred.Username,
+				"password": cred.Password,
+				"auth":     auth,
+			},
+		},
+	}
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Type: corev1.SecretTypeDockerConfigJson,
+		Data: map[string][]byte{
+			corev1.DockerConfigJsonKey: data,
+		},
+	}, nil
+}
+
+// pullSecretName derives a deterministic, DNS-label-safe Secret name from a
+// registry host so the same host always resolves to the same Secret.
+func pullSecretName(host string) string {
+	sanitized := strings.NewReplacer(".", "-", ":", "-", "/", "-").Replace(host)
+	return fmt.Sprintf("gitjob-pull-%s", sanitized)
+}
+
+func matchRegistryCredential(creds []RegistryCredential, host string) (RegistryCredential, bool) {
+	for _, c := range creds {
+		if c.Host == host {
+			return c, true
+		}
+	}
+	return RegistryCredential{}, false
+}
+
+// registryHost returns the registry host image will be pulled from,
+// defaulting to Docker Hub the way the Docker CLI does when no host is
+// present in the reference.
+func registryHost(image string) string {
+	parts := strings.SplitN(image, "/", 2)
+	if len(parts) == 1 {
+		return "docker.io"
+	}
+	if strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost" {
+		return parts[0]
+	}
+	return "docker.io"
+}
@@ -0,0 +1,113 @@
+package gitjob
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WellKnownSSHHosts are the hosts EnsureWellKnownHostsSecret scans by
+// default, covering the providers gitjob users most commonly clone from over
+// SSH.
+var WellKnownSSHHosts = []string{"github.com", "gitlab.com", "bitbucket.org"}
+
+// secretGetCreateUpdater is the subset of corev1controller.SecretController
+// EnsureWellKnownHostsSecret needs, narrow enough to fake in tests without a
+// live cluster.
+type secretGetCreateUpdater interface {
+	Get(namespace, name string, options metav1.GetOptions) (*corev1.Secret, error)
+	Create(*corev1.Secret) (*corev1.Secret, error)
+	Update(*corev1.Secret) (*corev1.Secret, error)
+}
+
+// scanHostKey dials host:22, captures the server's host key from the SSH
+// handshake, and returns it formatted as a known_hosts line. It never
+// completes authentication: the HostKeyCallback aborts the handshake as soon
+// as the key has been captured.
+func scanHostKey(host string) (string, error) {
+	return scanHostKeyAddr(host, net.JoinHostPort(host, "22"))
+}
+
+// scanHostKeyFn is scanHostKey, as a package variable so tests can stub out
+// the network dial when exercising EnsureWellKnownHostsSecret's create/
+// update/partial-failure logic.
+var scanHostKeyFn = scanHostKey
+
+// scanHostKeyAddr is scanHostKey split out so tests can point it at a local
+// SSH server listening on a non-standard address while still formatting the
+// known_hosts line for host.
+func scanHostKeyAddr(host, addr string) (string, error) {
+	var line string
+	cfg := &ssh.ClientConfig{
+		User:    "git",
+		Timeout: 5 * time.Second,
+		HostKeyCallback: func(_ string, _ net.Addr, key ssh.PublicKey) error {
+			line = knownhosts.Line([]string{host}, key)
+			return fmt.Errorf("host key captured")
+		},
+	}
+
+	conn, err := ssh.Dial("tcp", addr, cfg)
+	if conn != nil {
+		_ = conn.Close()
+	}
+	if line == "" {
+		return "", fmt.Errorf("scanning host key for %s: %w", host, err)
+	}
+	return line, nil
+}
+
+// EnsureWellKnownHostsSecret scans hosts for their current SSH host keys and
+// materializes the result into a controller-managed known_hosts Secret named
+// secretName in namespace, creating or replacing it as needed. generateJob
+// does not reference this Secret: a GitJob's init container only gets a
+// known_hosts file from the known_hosts key of its own SSH credential Secret.
+//
+// A host that fails to scan is logged and skipped rather than aborting the
+// whole call, so a transient failure reaching one provider doesn't discard
+// the keys already scanned for the others.
+func EnsureWellKnownHostsSecret(secretClient secretGetCreateUpdater, namespace, secretName string, hosts []string) error {
+	lines := make([]string, 0, len(hosts))
+	for _, host := range hosts {
+		line, err := scanHostKeyFn(host)
+		if err != nil {
+			logrus.Warnf("gitjob: skipping known_hosts entry for %s: %v", host, err)
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		return fmt.Errorf("failed to scan a host key for any of %v", hosts)
+	}
+
+	data := map[string][]byte{
+		knownHostsSecretKey: []byte(strings.Join(lines, "\n") + "\n"),
+	}
+
+	existing, err := secretClient.Get(namespace, secretName, metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		_, err = secretClient.Create(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      secretName,
+				Namespace: namespace,
+			},
+			Data: data,
+		})
+		return err
+	}
+
+	existing.Data = data
+	_, err = secretClient.Update(existing)
+	return err
+}
@@ -0,0 +1,195 @@
+package gitjob
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// startTestSSHServer listens on a loopback port and completes just enough of
+// an SSH handshake for a client to observe its host key, then returns the
+// listener's address and the host key it presents. The test server never
+// needs to finish authenticating a client: scanHostKeyAddr aborts the
+// handshake as soon as it has captured the key.
+func startTestSSHServer(t *testing.T) (string, ssh.PublicKey) {
+	t.Helper()
+
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generating host key: %v", err)
+	}
+	signer, err := ssh.NewSignerFromSigner(priv)
+	if err != nil {
+		t.Fatalf("building signer: %v", err)
+	}
+
+	config := &ssh.ServerConfig{NoClientAuth: true}
+	config.AddHostKey(signer)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listening: %v", err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		// The client aborts right after the host key exchange, so this
+		// always errors; that's expected.
+		_, _, _, _ = ssh.NewServerConn(conn, config)
+	}()
+
+	return listener.Addr().String(), signer.PublicKey()
+}
+
+func TestScanHostKeyAddr(t *testing.T) {
+	addr, hostKey := startTestSSHServer(t)
+
+	line, err := scanHostKeyAddr("example.invalid", addr)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.HasPrefix(line, "example.invalid ") {
+		t.Fatalf("expected line to start with the host name, got: %q", line)
+	}
+	if !strings.Contains(line, hostKey.Type()) {
+		t.Fatalf("expected line to contain the host key type %q, got: %q", hostKey.Type(), line)
+	}
+}
+
+func TestScanHostKeyAddr_Unreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listening: %v", err)
+	}
+	addr := listener.Addr().String()
+	_ = listener.Close()
+
+	if _, err := scanHostKeyAddr("example.invalid", addr); err == nil {
+		t.Fatalf("expected an error scanning an unreachable host")
+	}
+}
+
+// fakeSecretClient is a minimal secretGetCreateUpdater backed by a map,
+// standing in for a live cluster the way the rest of this package's tests
+// stand in for one with MockSecretCache.
+type fakeSecretClient struct {
+	secrets map[string]*corev1.Secret
+}
+
+func (f *fakeSecretClient) key(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+func (f *fakeSecretClient) Get(namespace, name string, _ metav1.GetOptions) (*corev1.Secret, error) {
+	secret, ok := f.secrets[f.key(namespace, name)]
+	if !ok {
+		return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, name)
+	}
+	return secret, nil
+}
+
+func (f *fakeSecretClient) Create(secret *corev1.Secret) (*corev1.Secret, error) {
+	if f.secrets == nil {
+		f.secrets = map[string]*corev1.Secret{}
+	}
+	if _, ok := f.secrets[f.key(secret.Namespace, secret.Name)]; ok {
+		return nil, apierrors.NewAlreadyExists(schema.GroupResource{Resource: "secrets"}, secret.Name)
+	}
+	f.secrets[f.key(secret.Namespace, secret.Name)] = secret
+	return secret, nil
+}
+
+func (f *fakeSecretClient) Update(secret *corev1.Secret) (*corev1.Secret, error) {
+	f.secrets[f.key(secret.Namespace, secret.Name)] = secret
+	return secret, nil
+}
+
+func TestEnsureWellKnownHostsSecret(t *testing.T) {
+	origScan := scanHostKeyFn
+	defer func() { scanHostKeyFn = origScan }()
+	scanHostKeyFn = func(host string) (string, error) {
+		if host == "unreachable.invalid" {
+			return "", fmt.Errorf("dial %s: connection refused", host)
+		}
+		return host + " ssh-ed25519 AAAA...", nil
+	}
+
+	t.Run("creates the secret when it doesn't exist", func(t *testing.T) {
+		client := &fakeSecretClient{}
+
+		if err := EnsureWellKnownHostsSecret(client, "ns", "known-hosts", []string{"good-a", "good-b"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		secret, err := client.Get("ns", "known-hosts", metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("expected secret to have been created: %v", err)
+		}
+		data := string(secret.Data[knownHostsSecretKey])
+		if !strings.Contains(data, "good-a ") || !strings.Contains(data, "good-b ") {
+			t.Fatalf("expected known_hosts data for both hosts, got: %q", data)
+		}
+	})
+
+	t.Run("updates an existing secret", func(t *testing.T) {
+		client := &fakeSecretClient{secrets: map[string]*corev1.Secret{
+			"ns/known-hosts": {
+				ObjectMeta: metav1.ObjectMeta{Name: "known-hosts", Namespace: "ns"},
+				Data:       map[string][]byte{knownHostsSecretKey: []byte("stale\n")},
+			},
+		}}
+
+		if err := EnsureWellKnownHostsSecret(client, "ns", "known-hosts", []string{"good-a"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		secret, _ := client.Get("ns", "known-hosts", metav1.GetOptions{})
+		data := string(secret.Data[knownHostsSecretKey])
+		if strings.Contains(data, "stale") {
+			t.Fatalf("expected stale data to be replaced, got: %q", data)
+		}
+		if !strings.Contains(data, "good-a ") {
+			t.Fatalf("expected known_hosts data for good-a, got: %q", data)
+		}
+	})
+
+	t.Run("a failing host is skipped, not fatal", func(t *testing.T) {
+		client := &fakeSecretClient{}
+
+		if err := EnsureWellKnownHostsSecret(client, "ns", "known-hosts", []string{"good-a", "unreachable.invalid"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		secret, _ := client.Get("ns", "known-hosts", metav1.GetOptions{})
+		data := string(secret.Data[knownHostsSecretKey])
+		if !strings.Contains(data, "good-a ") {
+			t.Fatalf("expected known_hosts data for good-a despite unreachable.invalid failing, got: %q", data)
+		}
+		if strings.Contains(data, "unreachable.invalid") {
+			t.Fatalf("expected no entry for the host that failed to scan, got: %q", data)
+		}
+	})
+
+	t.Run("every host failing is an error", func(t *testing.T) {
+		client := &fakeSecretClient{}
+
+		err := EnsureWellKnownHostsSecret(client, "ns", "known-hosts", []string{"unreachable.invalid"})
+		if err == nil {
+			t.Fatalf("expected an error when every host fails to scan")
+		}
+	})
+}
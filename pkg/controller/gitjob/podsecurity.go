@@ -0,0 +1,85 @@
+package gitjob
+
+import (
+	"fmt"
+
+	v1 "github.com/rancher/gitjob/pkg/apis/gitjob.cattle.io/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// applyPodSecurity enforces level on job's Pod: it rejects the host
+// namespaces and hostPath volumes that baseline/restricted forbid, and
+// patches every init and regular container's SecurityContext, including
+// user-supplied containers from the GitJob's JobSpec, to comply. Fields the
+// level doesn't govern (RunAsUser, RunAsGroup, added capabilities, ...) are
+// left as the user set them.
+func applyPodSecurity(job *batchv1.Job, level v1.PodSecurityLevel) error {
+	spec := &job.Spec.Template.Spec
+
+	if level == v1.PodSecurityPrivileged {
+		return nil
+	}
+
+	if spec.HostNetwork || spec.HostPID || spec.HostIPC {
+		return fmt.Errorf("podSecurity %q forbids hostNetwork, hostPID and hostIPC", level)
+	}
+	for _, v := range spec.Volumes {
+		if v.HostPath != nil {
+			return fmt.Errorf("podSecurity %q forbids hostPath volumes", level)
+		}
+	}
+
+	for i := range spec.InitContainers {
+		spec.InitContainers[i].SecurityContext = mergeSecurityContext(spec.InitContainers[i].SecurityContext, level)
+	}
+	for i := range spec.Containers {
+		spec.Containers[i].SecurityContext = mergeSecurityContext(spec.Containers[i].SecurityContext, level)
+	}
+	return nil
+}
+
+// mergeSecurityContext returns a copy of existing with the fields level
+// requires overridden, leaving every other field - RunAsUser, RunAsGroup,
+// added capabilities, and so on - untouched. This matters because an admin
+// may have set e.g. RunAsUser on a container specifically so it satisfies
+// the RunAsNonRoot this function also enforces; overwriting the whole
+// SecurityContext would silently discard that override and reintroduce the
+// very violation PodSecurity is meant to prevent.
+func mergeSecurityContext(existing *corev1.SecurityContext, level v1.PodSecurityLevel) *corev1.SecurityContext {
+	merged := &corev1.SecurityContext{}
+	if existing != nil {
+		merged = existing.DeepCopy()
+	}
+
+	merged.AllowPrivilegeEscalation = &[]bool{false}[0]
+	merged.Privileged = &[]bool{false}[0]
+	merged.Capabilities = dropAllCapability(merged.Capabilities)
+
+	if level == v1.PodSecurityBaseline {
+		return merged
+	}
+
+	merged.ReadOnlyRootFilesystem = &[]bool{true}[0]
+	merged.RunAsNonRoot = &[]bool{true}[0]
+	if merged.SeccompProfile == nil || (merged.SeccompProfile.Type != corev1.SeccompProfileTypeRuntimeDefault && merged.SeccompProfile.Type != corev1.SeccompProfileTypeLocalhost) {
+		merged.SeccompProfile = &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}
+	}
+	return merged
+}
+
+// dropAllCapability returns a copy of existing with "ALL" added to Drop, if
+// it isn't already present, preserving any Add the user configured.
+func dropAllCapability(existing *corev1.Capabilities) *corev1.Capabilities {
+	caps := &corev1.Capabilities{}
+	if existing != nil {
+		caps = existing.DeepCopy()
+	}
+	for _, c := range caps.Drop {
+		if c == "ALL" {
+			return caps
+		}
+	}
+	caps.Drop = append(caps.Drop, "ALL")
+	return caps
+}